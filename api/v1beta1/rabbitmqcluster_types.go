@@ -0,0 +1,138 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RabbitmqClusterPersistenceSpec configures the persistent volume claim
+// mounted by every server pod.
+type RabbitmqClusterPersistenceSpec struct {
+	// Storage is the amount of storage requested for the PVC.
+	Storage *k8sresource.Quantity `json:"storage,omitempty"`
+	// StorageClassName is the name of the StorageClass the PVC should use.
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// RabbitmqClusterSecretsSpec lets users bring their own pre-provisioned
+// secrets instead of having the operator generate and own them.
+type RabbitmqClusterSecretsSpec struct {
+	// ErlangCookieSecretRef points at a secret containing an ".erlang.cookie"
+	// key. When set, the operator does not generate its own erlang cookie
+	// secret and mounts this one instead.
+	ErlangCookieSecretRef *corev1.LocalObjectReference `json:"erlangCookieSecretRef,omitempty"`
+	// AdminSecretRef points at a secret containing "username" and "password"
+	// keys. When set, the operator does not generate its own admin secret
+	// and mounts this one instead.
+	AdminSecretRef *corev1.LocalObjectReference `json:"adminSecretRef,omitempty"`
+}
+
+// RabbitmqClusterConfigurationSpec configures the RabbitMQ broker itself,
+// as opposed to the Kubernetes objects wrapping it.
+type RabbitmqClusterConfigurationSpec struct {
+	// AdditionalPlugins is the list of RabbitMQ plugins to enable on top
+	// of the ones this operator always enables (rabbitmq_management,
+	// rabbitmq_prometheus). See PluginRegistry in internal/resource for
+	// the ports and env vars each supported plugin wires up.
+	AdditionalPlugins []string `json:"additionalPlugins,omitempty"`
+}
+
+// RabbitmqClusterTLSSpec configures TLS termination on the broker.
+type RabbitmqClusterTLSSpec struct {
+	// SecretRef points at a secret containing "tls.crt", "tls.key" and
+	// "ca.crt" keys. When set, the operator wires up the AMQPS (5671) and
+	// HTTPS management (15671) ports and the RABBITMQ_SSL_* environment
+	// variables required to terminate TLS on the broker.
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// RabbitmqClusterSpec defines the desired state of a RabbitmqCluster.
+type RabbitmqClusterSpec struct {
+	// Replicas is the number of RabbitMQ server pods to run.
+	Replicas int32 `json:"replicas,omitempty"`
+	// Image is the RabbitMQ server image to run.
+	Image string `json:"image,omitempty"`
+	// ImagePullSecret is the name of the secret used to pull Image, if
+	// private.
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+	// Resources are the resource requests/limits applied to the rabbitmq
+	// container.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Affinity applied to server pods.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations applied to server pods.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Persistence configures the PVC mounted by every server pod.
+	Persistence RabbitmqClusterPersistenceSpec `json:"persistence,omitempty"`
+	// Secrets lets users bring their own erlang cookie and admin secrets.
+	Secrets RabbitmqClusterSecretsSpec `json:"secrets,omitempty"`
+	// Rabbitmq holds broker-level configuration, such as which plugins to
+	// enable in addition to the ones this operator always turns on.
+	Rabbitmq RabbitmqClusterConfigurationSpec `json:"rabbitmq,omitempty"`
+	// TLS configures TLS termination on the broker.
+	TLS RabbitmqClusterTLSSpec `json:"tls,omitempty"`
+	// Override allows custom overrides of the generated StatefulSet and
+	// Service objects, for fields that aren't first-class on this spec.
+	Override RabbitmqClusterOverrideSpec `json:"override,omitempty"`
+	// RollingRestart lets users force a rolling restart of the cluster.
+	RollingRestart RabbitmqClusterRollingRestartSpec `json:"rollingRestart,omitempty"`
+}
+
+// RabbitmqClusterRollingRestartSpec lets users force a rolling restart of
+// every server pod without otherwise changing the cluster's configuration.
+type RabbitmqClusterRollingRestartSpec struct {
+	// Trigger is an arbitrary, user-controlled value. Changing it (e.g. to
+	// the current timestamp) forces the operator to roll every pod, the
+	// same way changing a ConfigMap or Secret it mounts would.
+	Trigger string `json:"trigger,omitempty"`
+}
+
+// RabbitmqClusterOverrideSpec holds raw, strategic-merge-patch-style YAML
+// overlays applied on top of objects the operator generates, for
+// customisation (sidecars, extra volumes, sysctls, priorityClassName,
+// topologySpreadConstraints, container args, ...) that isn't exposed as a
+// first-class field on RabbitmqClusterSpec.
+//
+// Only StatefulSet is supported (applied by StatefulSetBuilder.Update);
+// there is no override for the Services the operator generates.
+type RabbitmqClusterOverrideSpec struct {
+	// StatefulSet is merged onto the generated StatefulSet.
+	StatefulSet *runtime.RawExtension `json:"statefulSet,omitempty"`
+}
+
+// RabbitmqClusterStatus defines the observed state of a RabbitmqCluster.
+type RabbitmqClusterStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// RabbitmqCluster is the Schema for the rabbitmqclusters API.
+type RabbitmqCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RabbitmqClusterSpec   `json:"spec,omitempty"`
+	Status RabbitmqClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RabbitmqClusterList contains a list of RabbitmqCluster.
+type RabbitmqClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RabbitmqCluster `json:"items"`
+}
+
+// ChildResourceName returns the name of a child resource owned by this
+// RabbitmqCluster, e.g. the StatefulSet, Services and Secrets the operator
+// generates, namespaced under the cluster's own name.
+func (cluster *RabbitmqCluster) ChildResourceName(suffix string) string {
+	return fmt.Sprintf("%s-%s", cluster.Name, suffix)
+}
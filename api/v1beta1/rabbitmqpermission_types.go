@@ -0,0 +1,56 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RabbitmqPermissionSpec grants a user configure/write/read permissions on a
+// vhost of the cluster referenced by RabbitmqClusterName.
+type RabbitmqPermissionSpec struct {
+	// RabbitmqClusterName is the name of the RabbitmqCluster the user and
+	// vhost belong to. The cluster must exist in the same namespace.
+	RabbitmqClusterName string `json:"rabbitmqClusterName"`
+	// Vhost is the virtual host the permissions apply to.
+	Vhost string `json:"vhost"`
+	// User is the name of the RabbitMQ user the permissions are granted to.
+	User string `json:"user"`
+	// Configure is the regular expression matching resources the user can
+	// configure. Defaults to matching nothing.
+	Configure string `json:"configure,omitempty"`
+	// Write is the regular expression matching resources the user can
+	// write to. Defaults to matching nothing.
+	Write string `json:"write,omitempty"`
+	// Read is the regular expression matching resources the user can read
+	// from. Defaults to matching nothing.
+	Read string `json:"read,omitempty"`
+}
+
+// RabbitmqPermissionStatus reports the last observed state of the
+// permission grant on the broker.
+type RabbitmqPermissionStatus struct {
+	// Conditions describe the last transitions observed while reconciling
+	// this permission against the RabbitMQ HTTP API.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// RabbitmqPermission is the Schema for declaring a RabbitMQ user permission
+// grant as a first-class Kubernetes object.
+type RabbitmqPermission struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RabbitmqPermissionSpec   `json:"spec,omitempty"`
+	Status RabbitmqPermissionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RabbitmqPermissionList contains a list of RabbitmqPermission.
+type RabbitmqPermissionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RabbitmqPermission `json:"items"`
+}
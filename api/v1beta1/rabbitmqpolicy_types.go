@@ -0,0 +1,64 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RabbitmqPolicySpec describes a RabbitMQ policy to be applied to a vhost on
+// the cluster referenced by RabbitmqClusterName. It mirrors the policy
+// definition accepted by the RabbitMQ HTTP API
+// (PUT /api/policies/{vhost}/{name}).
+type RabbitmqPolicySpec struct {
+	// RabbitmqClusterName is the name of the RabbitmqCluster this policy
+	// should be applied to. The cluster must exist in the same namespace.
+	RabbitmqClusterName string `json:"rabbitmqClusterName"`
+	// Vhost is the virtual host the policy applies to. Defaults to "/".
+	// +kubebuilder:default:="/"
+	Vhost string `json:"vhost,omitempty"`
+	// Name is the name of the policy.
+	Name string `json:"name"`
+	// Pattern is the regular expression used to match queues/exchanges/both
+	// that the policy definition should be applied to.
+	Pattern string `json:"pattern"`
+	// ApplyTo restricts the policy to "queues", "exchanges" or "all".
+	// +kubebuilder:validation:Enum=queues;exchanges;all
+	// +kubebuilder:default:=all
+	ApplyTo string `json:"applyTo,omitempty"`
+	// Priority determines which policy applies when more than one policy
+	// matches a given queue or exchange. Higher numbers win.
+	// +kubebuilder:default:=0
+	Priority int `json:"priority,omitempty"`
+	// Definition is the arbitrary set of key-value pairs applied to matching
+	// queues/exchanges, e.g. ha-mode, ha-params, federation-upstream-set.
+	Definition map[string]string `json:"definition"`
+}
+
+// RabbitmqPolicyStatus reports the last observed state of the policy on the
+// broker.
+type RabbitmqPolicyStatus struct {
+	// Conditions describe the last transitions observed while reconciling
+	// this policy against the RabbitMQ HTTP API.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// RabbitmqPolicy is the Schema for declaring a RabbitMQ policy as a
+// first-class Kubernetes object.
+type RabbitmqPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RabbitmqPolicySpec   `json:"spec,omitempty"`
+	Status RabbitmqPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RabbitmqPolicyList contains a list of RabbitmqPolicy.
+type RabbitmqPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RabbitmqPolicy `json:"items"`
+}
@@ -0,0 +1,47 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RabbitmqVhostSpec describes a virtual host to be declared on the cluster
+// referenced by RabbitmqClusterName.
+type RabbitmqVhostSpec struct {
+	// RabbitmqClusterName is the name of the RabbitmqCluster this vhost
+	// should be declared on. The cluster must exist in the same namespace.
+	RabbitmqClusterName string `json:"rabbitmqClusterName"`
+	// Name is the name of the vhost to create.
+	Name string `json:"name"`
+	// Tracing enables firehose tracing for this vhost.
+	Tracing bool `json:"tracing,omitempty"`
+}
+
+// RabbitmqVhostStatus reports the last observed state of the vhost on the
+// broker.
+type RabbitmqVhostStatus struct {
+	// Conditions describe the last transitions observed while reconciling
+	// this vhost against the RabbitMQ HTTP API.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// RabbitmqVhost is the Schema for declaring a RabbitMQ vhost as a
+// first-class Kubernetes object.
+type RabbitmqVhost struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RabbitmqVhostSpec   `json:"spec,omitempty"`
+	Status RabbitmqVhostStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RabbitmqVhostList contains a list of RabbitmqVhost.
+type RabbitmqVhostList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RabbitmqVhost `json:"items"`
+}
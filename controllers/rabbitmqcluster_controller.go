@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"github.com/pivotal/rabbitmq-for-kubernetes/internal/resource"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// statefulSetName is the name of the StatefulSet sub-resource, matching
+// StatefulSetBuilder.statefulSet.
+const statefulSetName = "server"
+
+// RabbitmqClusterReconciler reconciles a RabbitmqCluster by driving the
+// builders implementing resource.SubResource - the admin secret and erlang
+// cookie secret (unless the user brings their own), the client Service and
+// the StatefulSet - through resource.ReconcileSubResources, so they are
+// what actually shapes the live cluster.
+type RabbitmqClusterReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+func (r *RabbitmqClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues("rabbitmqcluster", req.NamespacedName)
+
+	cluster := &rabbitmqv1beta1.RabbitmqCluster{}
+	if err := r.Get(ctx, req.NamespacedName, cluster); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if err := resource.ValidateReferencedSecrets(ctx, r.Client, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("referenced secret is invalid: %w", err)
+	}
+
+	var subs []resource.SubResource
+	if resource.ShouldGenerateAdminSecret(cluster) {
+		subs = append(subs, &resource.AdminSecretBuilder{Instance: cluster, Scheme: r.Scheme})
+	}
+	if resource.ShouldGenerateErlangCookieSecret(cluster) {
+		subs = append(subs, &resource.ErlangCookieSecretBuilder{Instance: cluster, Scheme: r.Scheme})
+	}
+	subs = append(subs, &resource.ClientServiceBuilder{Instance: cluster, Scheme: r.Scheme})
+	subs = append(subs, &resource.StatefulSetBuilder{Instance: cluster, Scheme: r.Scheme, Recorder: r.Recorder, Client: r.Client})
+
+	drifted, err := resource.ReconcileSubResources(subs,
+		func(kind resource.SubResourceKind) (runtime.Object, bool, error) {
+			return r.getCurrent(ctx, cluster, kind)
+		},
+		func(kind resource.SubResourceKind, desired runtime.Object, exists bool) error {
+			return r.write(ctx, cluster, kind, desired, exists)
+		},
+	)
+	if err != nil {
+		logger.Error(err, "failed to reconcile sub-resources")
+		return ctrl.Result{}, err
+	}
+	if len(drifted) > 0 {
+		logger.Info("reconciled drifted sub-resources", "kinds", drifted)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// getCurrent fetches the live object backing kind, reporting exists=false
+// when it hasn't been created yet, so ReconcileSubResources can decide
+// between Build+Create and Update.
+func (r *RabbitmqClusterReconciler) getCurrent(ctx context.Context, cluster *rabbitmqv1beta1.RabbitmqCluster, kind resource.SubResourceKind) (runtime.Object, bool, error) {
+	var obj client.Object
+	var name string
+
+	switch kind {
+	case resource.AdminSecretKind:
+		obj, name = &corev1.Secret{}, resource.AdminSecretName(cluster)
+	case resource.ErlangCookieKind:
+		obj, name = &corev1.Secret{}, resource.ErlangCookieSecretName(cluster)
+	case resource.ClientServiceKind:
+		obj, name = &corev1.Service{}, resource.ClientServiceName(cluster)
+	case resource.StatefulSetKind:
+		obj, name = &appsv1.StatefulSet{}, cluster.ChildResourceName(statefulSetName)
+	default:
+		return nil, false, fmt.Errorf("unsupported sub-resource kind %q", kind)
+	}
+
+	err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: cluster.Namespace}, obj)
+	if apierrors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return obj, true, nil
+}
+
+// write sets cluster as the owner of desired and creates or updates it,
+// depending on whether ReconcileSubResources found it already exists.
+func (r *RabbitmqClusterReconciler) write(ctx context.Context, cluster *rabbitmqv1beta1.RabbitmqCluster, kind resource.SubResourceKind, desired runtime.Object, exists bool) error {
+	obj, ok := desired.(client.Object)
+	if !ok {
+		return fmt.Errorf("%s does not satisfy client.Object", kind)
+	}
+
+	if err := controllerutil.SetControllerReference(cluster, obj, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on %s: %v", kind, err)
+	}
+
+	if exists {
+		return r.Update(ctx, obj)
+	}
+	return r.Create(ctx, obj)
+}
+
+func (r *RabbitmqClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor("rabbitmqcluster-controller")
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rabbitmqv1beta1.RabbitmqCluster{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Secret{}).
+		Owns(&corev1.Service{}).
+		Complete(r)
+}
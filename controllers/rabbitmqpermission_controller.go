@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"github.com/pivotal/rabbitmq-for-kubernetes/internal/resource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// permissionFinalizer keeps a RabbitmqPermission object around until its
+// grant has been revoked on the broker, so deleting the CR doesn't leave a
+// stale grant behind forever.
+const permissionFinalizer = "rabbitmq.com/permission-finalizer"
+
+// RabbitmqPermissionReconciler reconciles a RabbitmqPermission object
+// against the RabbitMQ HTTP API of the cluster it names.
+type RabbitmqPermissionReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *RabbitmqPermissionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues("rabbitmqpermission", req.NamespacedName)
+
+	permission := &rabbitmqv1beta1.RabbitmqPermission{}
+	if err := r.Get(ctx, req.NamespacedName, permission); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !permission.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, permission)
+	}
+
+	cluster := &rabbitmqv1beta1.RabbitmqCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Name: permission.Spec.RabbitmqClusterName, Namespace: permission.Namespace}, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get RabbitmqCluster %q: %w", permission.Spec.RabbitmqClusterName, err)
+	}
+
+	brokerClient, err := resource.BrokerClientForCluster(ctx, r.Client, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	builder := &resource.UserPermissionBuilder{Instance: cluster, Scheme: r.Scheme, Permission: permission}
+
+	if !controllerutil.ContainsFinalizer(permission, permissionFinalizer) {
+		controllerutil.AddFinalizer(permission, permissionFinalizer)
+		if err := r.Update(ctx, permission); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	if err := builder.Reconcile(brokerClient); err != nil {
+		logger.Error(err, "failed to reconcile permission")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileDelete revokes permission on the broker and, once that succeeds,
+// removes permissionFinalizer so the API server can finish deleting the
+// object. If the RabbitmqCluster (or its admin secret) is already gone -
+// e.g. it was deleted first and permission is being cascade-deleted along
+// with it - there's no broker left to revoke from, so the finalizer is
+// removed directly instead of requiring a live broker connection that will
+// never come back.
+func (r *RabbitmqPermissionReconciler) reconcileDelete(ctx context.Context, permission *rabbitmqv1beta1.RabbitmqPermission) error {
+	if !controllerutil.ContainsFinalizer(permission, permissionFinalizer) {
+		return nil
+	}
+
+	cluster := &rabbitmqv1beta1.RabbitmqCluster{}
+	err := r.Get(ctx, client.ObjectKey{Name: permission.Spec.RabbitmqClusterName, Namespace: permission.Namespace}, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		// Cluster is gone; nothing left to revoke from.
+	case err != nil:
+		return fmt.Errorf("failed to get RabbitmqCluster %q: %w", permission.Spec.RabbitmqClusterName, err)
+	default:
+		brokerClient, err := resource.BrokerClientForCluster(ctx, r.Client, cluster)
+		switch {
+		case apierrors.IsNotFound(err):
+			// Admin secret is gone along with the cluster being torn down;
+			// nothing left to revoke from.
+		case err != nil:
+			return err
+		default:
+			builder := &resource.UserPermissionBuilder{Instance: cluster, Scheme: r.Scheme, Permission: permission}
+			if err := builder.Delete(brokerClient); err != nil {
+				return fmt.Errorf("failed to delete permission from broker: %w", err)
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(permission, permissionFinalizer)
+	if err := r.Update(ctx, permission); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+func (r *RabbitmqPermissionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rabbitmqv1beta1.RabbitmqPermission{}).
+		Complete(r)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"github.com/pivotal/rabbitmq-for-kubernetes/internal/resource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// policyFinalizer keeps a RabbitmqPolicy object around until its policy has
+// been removed from the broker, so deleting the CR doesn't leave a stale
+// policy behind forever.
+const policyFinalizer = "rabbitmq.com/policy-finalizer"
+
+// RabbitmqPolicyReconciler reconciles a RabbitmqPolicy object against the
+// RabbitMQ HTTP API of the cluster it names.
+type RabbitmqPolicyReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *RabbitmqPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues("rabbitmqpolicy", req.NamespacedName)
+
+	policy := &rabbitmqv1beta1.RabbitmqPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !policy.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, policy)
+	}
+
+	cluster := &rabbitmqv1beta1.RabbitmqCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Name: policy.Spec.RabbitmqClusterName, Namespace: policy.Namespace}, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get RabbitmqCluster %q: %w", policy.Spec.RabbitmqClusterName, err)
+	}
+
+	brokerClient, err := resource.BrokerClientForCluster(ctx, r.Client, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	builder := &resource.PolicyBuilder{Instance: cluster, Scheme: r.Scheme, Policy: policy}
+
+	if !controllerutil.ContainsFinalizer(policy, policyFinalizer) {
+		controllerutil.AddFinalizer(policy, policyFinalizer)
+		if err := r.Update(ctx, policy); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	if err := builder.Reconcile(brokerClient); err != nil {
+		logger.Error(err, "failed to reconcile policy")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileDelete removes policy from the broker and, once that succeeds,
+// removes policyFinalizer so the API server can finish deleting the object.
+// If the RabbitmqCluster (or its admin secret) is already gone - e.g. it
+// was deleted first and policy is being cascade-deleted along with it -
+// there's no broker left to clean up, so the finalizer is removed directly
+// instead of requiring a live broker connection that will never come back.
+func (r *RabbitmqPolicyReconciler) reconcileDelete(ctx context.Context, policy *rabbitmqv1beta1.RabbitmqPolicy) error {
+	if !controllerutil.ContainsFinalizer(policy, policyFinalizer) {
+		return nil
+	}
+
+	cluster := &rabbitmqv1beta1.RabbitmqCluster{}
+	err := r.Get(ctx, client.ObjectKey{Name: policy.Spec.RabbitmqClusterName, Namespace: policy.Namespace}, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		// Cluster is gone; nothing left to delete from.
+	case err != nil:
+		return fmt.Errorf("failed to get RabbitmqCluster %q: %w", policy.Spec.RabbitmqClusterName, err)
+	default:
+		brokerClient, err := resource.BrokerClientForCluster(ctx, r.Client, cluster)
+		switch {
+		case apierrors.IsNotFound(err):
+			// Admin secret is gone along with the cluster being torn down;
+			// nothing left to delete from.
+		case err != nil:
+			return err
+		default:
+			builder := &resource.PolicyBuilder{Instance: cluster, Scheme: r.Scheme, Policy: policy}
+			if err := builder.Delete(brokerClient); err != nil {
+				return fmt.Errorf("failed to delete policy from broker: %w", err)
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(policy, policyFinalizer)
+	if err := r.Update(ctx, policy); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+func (r *RabbitmqPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rabbitmqv1beta1.RabbitmqPolicy{}).
+		Complete(r)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"github.com/pivotal/rabbitmq-for-kubernetes/internal/resource"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// vhostFinalizer keeps a RabbitmqVhost object around until its vhost has
+// been removed from the broker, so deleting the CR doesn't leave a stale
+// vhost behind forever.
+const vhostFinalizer = "rabbitmq.com/vhost-finalizer"
+
+// RabbitmqVhostReconciler reconciles a RabbitmqVhost object against the
+// RabbitMQ HTTP API of the cluster it names.
+type RabbitmqVhostReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *RabbitmqVhostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues("rabbitmqvhost", req.NamespacedName)
+
+	vhost := &rabbitmqv1beta1.RabbitmqVhost{}
+	if err := r.Get(ctx, req.NamespacedName, vhost); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !vhost.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, vhost)
+	}
+
+	cluster := &rabbitmqv1beta1.RabbitmqCluster{}
+	if err := r.Get(ctx, client.ObjectKey{Name: vhost.Spec.RabbitmqClusterName, Namespace: vhost.Namespace}, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get RabbitmqCluster %q: %w", vhost.Spec.RabbitmqClusterName, err)
+	}
+
+	brokerClient, err := resource.BrokerClientForCluster(ctx, r.Client, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	builder := &resource.VhostBuilder{Instance: cluster, Scheme: r.Scheme, Vhost: vhost}
+
+	if !controllerutil.ContainsFinalizer(vhost, vhostFinalizer) {
+		controllerutil.AddFinalizer(vhost, vhostFinalizer)
+		if err := r.Update(ctx, vhost); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	if err := builder.Reconcile(brokerClient); err != nil {
+		logger.Error(err, "failed to reconcile vhost")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileDelete removes vhost from the broker and, once that succeeds,
+// removes vhostFinalizer so the API server can finish deleting the object.
+// If the RabbitmqCluster (or its admin secret) is already gone - e.g. it
+// was deleted first and vhost is being cascade-deleted along with it -
+// there's no broker left to clean up, so the finalizer is removed directly
+// instead of requiring a live broker connection that will never come back.
+func (r *RabbitmqVhostReconciler) reconcileDelete(ctx context.Context, vhost *rabbitmqv1beta1.RabbitmqVhost) error {
+	if !controllerutil.ContainsFinalizer(vhost, vhostFinalizer) {
+		return nil
+	}
+
+	cluster := &rabbitmqv1beta1.RabbitmqCluster{}
+	err := r.Get(ctx, client.ObjectKey{Name: vhost.Spec.RabbitmqClusterName, Namespace: vhost.Namespace}, cluster)
+	switch {
+	case apierrors.IsNotFound(err):
+		// Cluster is gone; nothing left to delete from.
+	case err != nil:
+		return fmt.Errorf("failed to get RabbitmqCluster %q: %w", vhost.Spec.RabbitmqClusterName, err)
+	default:
+		brokerClient, err := resource.BrokerClientForCluster(ctx, r.Client, cluster)
+		switch {
+		case apierrors.IsNotFound(err):
+			// Admin secret is gone along with the cluster being torn down;
+			// nothing left to delete from.
+		case err != nil:
+			return err
+		default:
+			builder := &resource.VhostBuilder{Instance: cluster, Scheme: r.Scheme, Vhost: vhost}
+			if err := builder.Delete(brokerClient); err != nil {
+				return fmt.Errorf("failed to delete vhost from broker: %w", err)
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(vhost, vhostFinalizer)
+	if err := r.Update(ctx, vhost); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+func (r *RabbitmqVhostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rabbitmqv1beta1.RabbitmqVhost{}).
+		Complete(r)
+}
@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// adminUsername is the username stamped into the operator-generated admin
+// secret. Users who bring their own secret via Spec.Secrets.AdminSecretRef
+// choose their own username instead.
+const adminUsername = "admin"
+
+// AdminSecretBuilder builds and maintains the admin secret mounted by
+// StatefulSetBuilder. It's only ever added to a reconcile loop's
+// sub-resources when ShouldGenerateAdminSecret reports the user hasn't
+// brought their own via Spec.Secrets.AdminSecretRef.
+type AdminSecretBuilder struct {
+	Instance *rabbitmqv1beta1.RabbitmqCluster
+	Scheme   *runtime.Scheme
+}
+
+func (builder *AdminSecretBuilder) Kind() SubResourceKind {
+	return AdminSecretKind
+}
+
+func (builder *AdminSecretBuilder) Build() (runtime.Object, error) {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AdminSecretName(builder.Instance),
+			Namespace: builder.Instance.Namespace,
+		},
+	}, nil
+}
+
+// Update populates secret's "username"/"password" keys the first time it's
+// created, and otherwise leaves them alone - rotating the password here
+// would disconnect anyone already authenticated with the old one, so
+// rotation is left to whatever process manages a secret once a user opts
+// into bringing their own via Spec.Secrets.AdminSecretRef.
+func (builder *AdminSecretBuilder) Update(current runtime.Object) error {
+	secret := current.(*corev1.Secret)
+	if ValidateSecretKeys(secret, RequiredAdminSecretKeys...) == nil {
+		return nil
+	}
+
+	password, err := randomHexString(24)
+	if err != nil {
+		return fmt.Errorf("failed to generate admin password: %v", err)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data["username"] = []byte(adminUsername)
+	secret.Data["password"] = []byte(password)
+	return nil
+}
+
+// Matches reports whether secret already has the keys RabbitMQ needs to
+// authenticate, so Update only ever runs once per secret.
+func (builder *AdminSecretBuilder) Matches(current runtime.Object) bool {
+	secret, ok := current.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+	return ValidateSecretKeys(secret, RequiredAdminSecretKeys...) == nil
+}
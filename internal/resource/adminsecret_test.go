@@ -0,0 +1,31 @@
+package resource
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAdminSecretBuilderUpdateGeneratesOnce(t *testing.T) {
+	builder := &AdminSecretBuilder{Instance: &rabbitmqv1beta1.RabbitmqCluster{}}
+	secret := &corev1.Secret{}
+
+	if err := builder.Update(secret); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if len(secret.Data["username"]) == 0 || len(secret.Data["password"]) == 0 {
+		t.Fatalf("Update() did not populate username/password: %v", secret.Data)
+	}
+	if !builder.Matches(secret) {
+		t.Fatalf("Matches() = false after Update populated the required keys")
+	}
+
+	password := string(secret.Data["password"])
+	if err := builder.Update(secret); err != nil {
+		t.Fatalf("second Update() error = %v", err)
+	}
+	if string(secret.Data["password"]) != password {
+		t.Errorf("Update() rotated an existing password; want it left untouched")
+	}
+}
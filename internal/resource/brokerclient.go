@@ -0,0 +1,50 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagementURI returns the in-cluster URL of the RabbitMQ management API
+// for instance, reachable from within the operator's namespace.
+func ManagementURI(instance *rabbitmqv1beta1.RabbitmqCluster) string {
+	return fmt.Sprintf("http://%s.%s.svc:15672", ClientServiceName(instance), instance.Namespace)
+}
+
+// NewBrokerClient builds a RabbitMQ HTTP API client for instance using the
+// username and password already produced in its admin secret, so callers
+// reconciling PolicyBuilder, VhostBuilder and UserPermissionBuilder don't
+// each have to know how to authenticate against the broker.
+func NewBrokerClient(instance *rabbitmqv1beta1.RabbitmqCluster, username, password string) (*rabbithole.Client, error) {
+	client, err := rabbithole.NewClient(ManagementURI(instance), username, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RabbitMQ HTTP API client: %v", err)
+	}
+	return client, nil
+}
+
+// BrokerClientForCluster fetches instance's admin secret (the
+// operator-generated one, or the user's own when Spec.Secrets.AdminSecretRef
+// is set) via k8sClient and uses its "username"/"password" keys to build a
+// RabbitMQ HTTP API client. This is the one place RabbitmqPolicy/
+// RabbitmqVhost/RabbitmqPermission controllers need to go from "which
+// cluster is this for" to "something I can call PutPolicy/PutVhost/
+// UpdatePermissionsIn on".
+func BrokerClientForCluster(ctx context.Context, k8sClient client.Client, instance *rabbitmqv1beta1.RabbitmqCluster) (*rabbithole.Client, error) {
+	secret := &corev1.Secret{}
+	secretName := AdminSecretName(instance)
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: secretName, Namespace: instance.Namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get admin secret %q: %w", secretName, err)
+	}
+
+	if err := ValidateSecretKeys(secret, RequiredAdminSecretKeys...); err != nil {
+		return nil, err
+	}
+
+	return NewBrokerClient(instance, string(secret.Data["username"]), string(secret.Data["password"]))
+}
@@ -0,0 +1,84 @@
+package resource
+
+import (
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"github.com/pivotal/rabbitmq-for-kubernetes/internal/metadata"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// clientServiceName is the suffix ChildResourceName appends for the
+// client-facing Service, matching the name BrokerClientForCluster already
+// assumes when it builds the broker's base URL.
+const clientServiceName = "client"
+
+// ClientServiceBuilder builds the Service clients use to reach the
+// cluster's amqp/management ports, adding a prometheus port only when
+// PrometheusEnabled reports rabbitmq_prometheus is running. A full
+// ServiceMonitor (the prometheus-operator CRD) is out of scope here: this
+// repo has no dependency on prometheus-operator's API types, so wiring one
+// up would mean vendoring a CRD client this operator otherwise never
+// needs. Exposing the port on a plain Service is the scope this change
+// covers; a ServiceMonitor can be layered on top of it separately.
+type ClientServiceBuilder struct {
+	Instance *rabbitmqv1beta1.RabbitmqCluster
+	Scheme   *runtime.Scheme
+}
+
+func (builder *ClientServiceBuilder) Kind() SubResourceKind {
+	return ClientServiceKind
+}
+
+func (builder *ClientServiceBuilder) Build() (runtime.Object, error) {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClientServiceName(builder.Instance),
+			Namespace: builder.Instance.Namespace,
+		},
+	}, nil
+}
+
+// Update sets service's selector and ports to the desired state. The
+// amqp/http ports are always present; the prometheus port is only added
+// when PrometheusEnabled(builder.Instance) is true, so scraping follows
+// the plugin the same way basePodTemplateAnnotations does.
+func (builder *ClientServiceBuilder) Update(current runtime.Object) error {
+	service := current.(*corev1.Service)
+	service.Spec.Selector = metadata.LabelSelector(builder.Instance.Name)
+	service.Spec.Ports = builder.desiredPorts()
+	return nil
+}
+
+// Matches reports whether service's ports already match desiredPorts,
+// ignoring fields the Kubernetes API server defaults on Create (NodePort,
+// ClusterIP) that this builder never sets.
+func (builder *ClientServiceBuilder) Matches(current runtime.Object) bool {
+	service, ok := current.(*corev1.Service)
+	if !ok {
+		return false
+	}
+
+	desired := builder.desiredPorts()
+	if len(service.Spec.Ports) != len(desired) {
+		return false
+	}
+	for i, port := range desired {
+		existing := service.Spec.Ports[i]
+		if existing.Name != port.Name || existing.Port != port.Port {
+			return false
+		}
+	}
+	return true
+}
+
+func (builder *ClientServiceBuilder) desiredPorts() []corev1.ServicePort {
+	ports := []corev1.ServicePort{
+		{Name: "amqp", Port: 5672},
+		{Name: "http", Port: 15672},
+	}
+	if PrometheusEnabled(builder.Instance) {
+		ports = append(ports, corev1.ServicePort{Name: "prometheus", Port: 15692})
+	}
+	return ports
+}
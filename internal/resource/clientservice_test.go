@@ -0,0 +1,30 @@
+package resource
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestClientServiceBuilderUpdateAddsPrometheusPort(t *testing.T) {
+	builder := &ClientServiceBuilder{Instance: &rabbitmqv1beta1.RabbitmqCluster{}}
+	service := &corev1.Service{}
+
+	if err := builder.Update(service); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	found := false
+	for _, port := range service.Spec.Ports {
+		if port.Name == "prometheus" && port.Port == 15692 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Update() ports = %v, want a prometheus:15692 port since rabbitmq_prometheus is a default plugin", service.Spec.Ports)
+	}
+	if !builder.Matches(service) {
+		t.Errorf("Matches() = false right after Update")
+	}
+}
@@ -0,0 +1,64 @@
+package resource
+
+import (
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ErlangCookieSecretBuilder builds and maintains the erlang cookie secret
+// mounted by StatefulSetBuilder. It's only ever added to a reconcile loop's
+// sub-resources when ShouldGenerateErlangCookieSecret reports the user
+// hasn't brought their own via Spec.Secrets.ErlangCookieSecretRef.
+type ErlangCookieSecretBuilder struct {
+	Instance *rabbitmqv1beta1.RabbitmqCluster
+	Scheme   *runtime.Scheme
+}
+
+func (builder *ErlangCookieSecretBuilder) Kind() SubResourceKind {
+	return ErlangCookieKind
+}
+
+func (builder *ErlangCookieSecretBuilder) Build() (runtime.Object, error) {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ErlangCookieSecretName(builder.Instance),
+			Namespace: builder.Instance.Namespace,
+		},
+	}, nil
+}
+
+// Update populates secret's ".erlang.cookie" key the first time it's
+// created. Every server pod mounts the same secret, so rotating it here
+// would desync any node that hasn't picked up the change yet - it's
+// generated once and left alone for the lifetime of the cluster.
+func (builder *ErlangCookieSecretBuilder) Update(current runtime.Object) error {
+	secret := current.(*corev1.Secret)
+	if ValidateSecretKeys(secret, RequiredErlangCookieSecretKeys...) == nil {
+		return nil
+	}
+
+	cookie, err := randomHexString(20)
+	if err != nil {
+		return fmt.Errorf("failed to generate erlang cookie: %v", err)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[".erlang.cookie"] = []byte(cookie)
+	return nil
+}
+
+// Matches reports whether secret already has the erlang cookie key, so
+// Update only ever runs once per secret.
+func (builder *ErlangCookieSecretBuilder) Matches(current runtime.Object) bool {
+	secret, ok := current.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+	return ValidateSecretKeys(secret, RequiredErlangCookieSecretKeys...) == nil
+}
@@ -0,0 +1,59 @@
+package resource
+
+import (
+	"fmt"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func (builder *RabbitmqResourceBuilder) UserPermission(permission *rabbitmqv1beta1.RabbitmqPermission) *UserPermissionBuilder {
+	return &UserPermissionBuilder{
+		Instance:   builder.Instance,
+		Scheme:     builder.Scheme,
+		Permission: permission,
+	}
+}
+
+// UserPermissionBuilder reconciles a RabbitmqPermission against the
+// RabbitMQ HTTP API of the broker owning Instance. As with PolicyBuilder,
+// there is no backing Kubernetes object, so reconciliation is exposed as
+// Reconcile rather than Update.
+type UserPermissionBuilder struct {
+	Instance   *rabbitmqv1beta1.RabbitmqCluster
+	Scheme     *runtime.Scheme
+	Permission *rabbitmqv1beta1.RabbitmqPermission
+}
+
+// Build returns the rabbithole permissions ready to be sent to the HTTP
+// API.
+func (builder *UserPermissionBuilder) Build() rabbithole.Permissions {
+	spec := builder.Permission.Spec
+	return rabbithole.Permissions{
+		Configure: spec.Configure,
+		Write:     spec.Write,
+		Read:      spec.Read,
+	}
+}
+
+// Reconcile grants the desired permissions to the broker pointed to by
+// client, creating or updating them as needed.
+func (builder *UserPermissionBuilder) Reconcile(client *rabbithole.Client) error {
+	spec := builder.Permission.Spec
+	if _, err := client.UpdatePermissionsIn(spec.Vhost, spec.User, builder.Build()); err != nil {
+		return fmt.Errorf("failed to reconcile permissions for user %q on vhost %q: %v", spec.User, spec.Vhost, err)
+	}
+	return nil
+}
+
+// Delete revokes the permission grant from the broker pointed to by client.
+// It is called by RabbitmqPermissionReconciler when the RabbitmqPermission
+// is deleted, so the grant doesn't outlive its Kubernetes object.
+func (builder *UserPermissionBuilder) Delete(client *rabbithole.Client) error {
+	spec := builder.Permission.Spec
+	if _, err := client.ClearPermissionsIn(spec.Vhost, spec.User); err != nil {
+		return fmt.Errorf("failed to delete permissions for user %q on vhost %q: %v", spec.User, spec.Vhost, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package resource
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+)
+
+func TestUserPermissionBuilderBuild(t *testing.T) {
+	builder := &UserPermissionBuilder{
+		Permission: &rabbitmqv1beta1.RabbitmqPermission{
+			Spec: rabbitmqv1beta1.RabbitmqPermissionSpec{
+				Vhost:     "/",
+				User:      "app",
+				Configure: "^app-.*",
+				Write:     "^app-.*",
+				Read:      ".*",
+			},
+		},
+	}
+
+	permissions := builder.Build()
+
+	if permissions.Configure != "^app-.*" {
+		t.Errorf("Configure = %q, want %q", permissions.Configure, "^app-.*")
+	}
+	if permissions.Write != "^app-.*" {
+		t.Errorf("Write = %q, want %q", permissions.Write, "^app-.*")
+	}
+	if permissions.Read != ".*" {
+		t.Errorf("Read = %q, want %q", permissions.Read, ".*")
+	}
+}
@@ -0,0 +1,182 @@
+package resource
+
+import (
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// corePorts are exposed regardless of which plugins are enabled: epmd for
+// inter-node/CLI clustering and amqp for the core messaging protocol.
+var corePorts = []corev1.ContainerPort{
+	{Name: "epmd", ContainerPort: 4369},
+	{Name: "amqp", ContainerPort: 5672},
+}
+
+// defaultPlugins are enabled on every cluster regardless of
+// Spec.Rabbitmq.AdditionalPlugins, preserving the ports this operator has
+// always exposed.
+var defaultPlugins = []string{"rabbitmq_management", "rabbitmq_prometheus"}
+
+// RabbitmqPlugin describes the ports, environment variables and readiness
+// probe a RabbitMQ plugin needs wired into the server container when it's
+// enabled.
+type RabbitmqPlugin struct {
+	Ports []corev1.ContainerPort
+	Env   []corev1.EnvVar
+	// Probe overrides the container's readiness probe while this plugin is
+	// enabled, for plugins whose health can't be inferred from
+	// check_port_connectivity alone. Most plugins leave this nil and rely
+	// on the base probe.
+	Probe *corev1.Probe
+}
+
+// managementReadinessProbe is used in place of the base check_port_connectivity
+// probe when rabbitmq_management is enabled: check_running additionally
+// confirms the node has finished booting, which the management HTTP API
+// depends on to answer anything useful.
+var managementReadinessProbe = &corev1.Probe{
+	Handler: corev1.Handler{
+		Exec: &corev1.ExecAction{
+			Command: []string{"/bin/sh", "-c", "rabbitmq-diagnostics check_running && rabbitmq-diagnostics check_port_connectivity"},
+		},
+	},
+	InitialDelaySeconds: 10,
+	TimeoutSeconds:      5,
+	PeriodSeconds:       30,
+	SuccessThreshold:    1,
+	FailureThreshold:    3,
+}
+
+// PluginRegistry declares, per plugin name, the container ports, env vars
+// and probe StatefulSetBuilder.podTemplateSpec must wire in when that
+// plugin is enabled. This replaces a static port array and a single
+// hardcoded probe with a data-driven model so enabling
+// rabbitmq_mqtt/rabbitmq_stomp/rabbitmq_stream is enough to get their ports
+// wired up, and so Prometheus scraping follows rabbitmq_prometheus rather
+// than always being on.
+var PluginRegistry = map[string]RabbitmqPlugin{
+	"rabbitmq_management": {
+		Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 15672}},
+		Probe: managementReadinessProbe,
+	},
+	"rabbitmq_prometheus": {
+		Ports: []corev1.ContainerPort{{Name: "prometheus", ContainerPort: 15692}},
+	},
+	"rabbitmq_mqtt": {
+		Ports: []corev1.ContainerPort{{Name: "mqtt", ContainerPort: 1883}},
+	},
+	"rabbitmq_stomp": {
+		Ports: []corev1.ContainerPort{{Name: "stomp", ContainerPort: 61613}},
+	},
+	"rabbitmq_stream": {
+		Ports: []corev1.ContainerPort{{Name: "stream", ContainerPort: 5552}},
+	},
+	"rabbitmq_federation": {},
+	"rabbitmq_shovel":     {},
+}
+
+// EnabledPlugins returns the deduplicated set of plugins instance will run:
+// the plugins this operator always enables, plus whatever the user listed
+// in Spec.Rabbitmq.AdditionalPlugins. It's a free function rather than a
+// StatefulSetBuilder method so ClientServiceBuilder can also consult it
+// without depending on the StatefulSet builder.
+func EnabledPlugins(instance *rabbitmqv1beta1.RabbitmqCluster) []string {
+	seen := make(map[string]bool, len(defaultPlugins)+len(instance.Spec.Rabbitmq.AdditionalPlugins))
+	var enabled []string
+	for _, plugin := range append(append([]string{}, defaultPlugins...), instance.Spec.Rabbitmq.AdditionalPlugins...) {
+		if seen[plugin] {
+			continue
+		}
+		seen[plugin] = true
+		enabled = append(enabled, plugin)
+	}
+	return enabled
+}
+
+// PrometheusEnabled reports whether rabbitmq_prometheus is among instance's
+// enabled plugins, so both StatefulSetBuilder (scrape annotations) and
+// ClientServiceBuilder (the prometheus Service port) only emit their
+// Prometheus-specific wiring when it's actually running.
+func PrometheusEnabled(instance *rabbitmqv1beta1.RabbitmqCluster) bool {
+	for _, plugin := range EnabledPlugins(instance) {
+		if plugin == "rabbitmq_prometheus" {
+			return true
+		}
+	}
+	return false
+}
+
+// EnabledPlugins returns the deduplicated set of plugins this instance will
+// run. See the EnabledPlugins free function.
+func (builder *StatefulSetBuilder) EnabledPlugins() []string {
+	return EnabledPlugins(builder.Instance)
+}
+
+// PrometheusEnabled reports whether rabbitmq_prometheus is among the
+// enabled plugins. See the PrometheusEnabled free function.
+func (builder *StatefulSetBuilder) PrometheusEnabled() bool {
+	return PrometheusEnabled(builder.Instance)
+}
+
+// pluginPorts returns the union of corePorts and the ports declared by
+// every enabled plugin in PluginRegistry.
+func (builder *StatefulSetBuilder) pluginPorts() []corev1.ContainerPort {
+	ports := append([]corev1.ContainerPort{}, corePorts...)
+	for _, name := range builder.EnabledPlugins() {
+		ports = append(ports, PluginRegistry[name].Ports...)
+	}
+	return ports
+}
+
+// pluginEnv returns the environment variables contributed by every enabled
+// plugin in PluginRegistry.
+func (builder *StatefulSetBuilder) pluginEnv() []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, name := range builder.EnabledPlugins() {
+		env = append(env, PluginRegistry[name].Env...)
+	}
+	return env
+}
+
+// basePodTemplateAnnotations returns the pod template annotations driven by
+// enabled plugins - today, the Prometheus scrape annotations consumed by a
+// vanilla Prometheus server when rabbitmq_prometheus is enabled, so
+// monitoring follows the plugin instead of being always-on.
+func (builder *StatefulSetBuilder) basePodTemplateAnnotations() map[string]string {
+	if !builder.PrometheusEnabled() {
+		return nil
+	}
+	return map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "15692",
+	}
+}
+
+// readinessProbe returns the check_port_connectivity probe every cluster
+// gets, replaced by the probe of the last plugin in EnabledPlugins order
+// that declares one in PluginRegistry; this is currently only
+// rabbitmq_management's check_running-and-check_port_connectivity probe,
+// since it's the first plugin whose own health isn't fully captured by
+// port connectivity alone.
+func (builder *StatefulSetBuilder) readinessProbe() *corev1.Probe {
+	probe := &corev1.Probe{
+		Handler: corev1.Handler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/bin/sh", "-c", "rabbitmq-diagnostics check_port_connectivity"},
+			},
+		},
+		InitialDelaySeconds: 10,
+		TimeoutSeconds:      5,
+		PeriodSeconds:       30,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
+	}
+
+	for _, name := range builder.EnabledPlugins() {
+		if p := PluginRegistry[name].Probe; p != nil {
+			probe = p
+		}
+	}
+
+	return probe
+}
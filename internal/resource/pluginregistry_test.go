@@ -0,0 +1,53 @@
+package resource
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+)
+
+func TestPrometheusEnabled(t *testing.T) {
+	instance := &rabbitmqv1beta1.RabbitmqCluster{}
+	if !PrometheusEnabled(instance) {
+		t.Errorf("PrometheusEnabled() = false, want true: rabbitmq_prometheus is a default plugin")
+	}
+
+	instance.Spec.Rabbitmq.AdditionalPlugins = []string{"rabbitmq_mqtt"}
+	if !PrometheusEnabled(instance) {
+		t.Errorf("PrometheusEnabled() = false, want true: additional plugins don't replace the defaults")
+	}
+}
+
+func TestStatefulSetBuilderPluginPorts(t *testing.T) {
+	builder := &StatefulSetBuilder{
+		Instance: &rabbitmqv1beta1.RabbitmqCluster{},
+	}
+	builder.Instance.Spec.Rabbitmq.AdditionalPlugins = []string{"rabbitmq_mqtt"}
+
+	ports := builder.pluginPorts()
+
+	var names []string
+	for _, port := range ports {
+		names = append(names, port.Name)
+	}
+
+	for _, want := range []string{"epmd", "amqp", "http", "prometheus", "mqtt"} {
+		found := false
+		for _, name := range names {
+			if name == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("pluginPorts() = %v, want it to include %q", names, want)
+		}
+	}
+}
+
+func TestStatefulSetBuilderReadinessProbe(t *testing.T) {
+	probe := (&StatefulSetBuilder{Instance: &rabbitmqv1beta1.RabbitmqCluster{}}).readinessProbe()
+	if probe != managementReadinessProbe {
+		t.Errorf("readinessProbe() = %v, want managementReadinessProbe: rabbitmq_management is a default plugin", probe)
+	}
+}
@@ -0,0 +1,50 @@
+package resource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PodTemplateHashAnnotation records a stable hash of the generated pod
+	// template, plus the resource versions of the ConfigMap/Secrets it
+	// references, so Update can tell a real configuration change from a
+	// no-op reconcile without relying solely on ReconcileAnnotations.
+	PodTemplateHashAnnotation = "rabbitmq.com/pod-template-hash"
+	// RestartRequestedAtAnnotation is stamped onto the pod template
+	// whenever Spec.RollingRestart.Trigger changes, forcing the
+	// StatefulSet controller to roll every pod even though the rest of
+	// the template is unchanged.
+	RestartRequestedAtAnnotation = "rabbitmq.com/restart-requested-at"
+)
+
+// podTemplateHash returns a stable hash of template combined with the
+// resource versions of the ConfigMap/Secrets it references. Two templates
+// that are byte-for-byte identical, mounting secrets whose contents have
+// since changed underneath them, hash differently because their resource
+// versions differ - this is what lets Update detect config/secret drift
+// without diffing the whole object tree. PodTemplateHashAnnotation and
+// RestartRequestedAtAnnotation are left out of what is hashed: the former
+// would otherwise feed each stamped hash into the next one, and the latter
+// is compared on its own.
+func podTemplateHash(template corev1.PodTemplateSpec, referencedResourceVersions ...string) (string, error) {
+	template = *template.DeepCopy()
+	delete(template.Annotations, PodTemplateHashAnnotation)
+	delete(template.Annotations, RestartRequestedAtAnnotation)
+
+	marshaled, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+	hasher.Write(marshaled)
+	for _, resourceVersion := range referencedResourceVersions {
+		hasher.Write([]byte(resourceVersion))
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil))[:10], nil
+}
@@ -0,0 +1,49 @@
+package resource
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodTemplateHashDeterministic(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "rabbitmq", Image: "rabbitmq:3.11"}},
+		},
+	}
+
+	first, err := podTemplateHash(template, "1", "2")
+	if err != nil {
+		t.Fatalf("podTemplateHash() error = %v", err)
+	}
+	second, err := podTemplateHash(template, "1", "2")
+	if err != nil {
+		t.Fatalf("podTemplateHash() error = %v", err)
+	}
+
+	if first != second {
+		t.Errorf("podTemplateHash() is not deterministic: %q != %q", first, second)
+	}
+}
+
+func TestPodTemplateHashChangesWithReferencedResourceVersions(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "rabbitmq", Image: "rabbitmq:3.11"}},
+		},
+	}
+
+	before, err := podTemplateHash(template, "1")
+	if err != nil {
+		t.Fatalf("podTemplateHash() error = %v", err)
+	}
+	after, err := podTemplateHash(template, "2")
+	if err != nil {
+		t.Fatalf("podTemplateHash() error = %v", err)
+	}
+
+	if before == after {
+		t.Errorf("podTemplateHash() did not change when a referenced resourceVersion changed: both %q", before)
+	}
+}
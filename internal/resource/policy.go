@@ -0,0 +1,72 @@
+package resource
+
+import (
+	"fmt"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func (builder *RabbitmqResourceBuilder) Policy(policy *rabbitmqv1beta1.RabbitmqPolicy) *PolicyBuilder {
+	return &PolicyBuilder{
+		Instance: builder.Instance,
+		Scheme:   builder.Scheme,
+		Policy:   policy,
+	}
+}
+
+// PolicyBuilder reconciles a RabbitmqPolicy against the RabbitMQ HTTP API of
+// the broker owning Instance. Unlike the child-object builders in this
+// package, it has no corresponding Kubernetes object to create - the policy
+// lives only inside the broker - so it exposes Reconcile instead of Update.
+type PolicyBuilder struct {
+	Instance *rabbitmqv1beta1.RabbitmqCluster
+	Scheme   *runtime.Scheme
+	Policy   *rabbitmqv1beta1.RabbitmqPolicy
+}
+
+// Build returns the rabbithole policy ready to be sent to the HTTP API.
+func (builder *PolicyBuilder) Build() rabbithole.Policy {
+	spec := builder.Policy.Spec
+	vhost := spec.Vhost
+	if vhost == "" {
+		vhost = "/"
+	}
+
+	definition := make(rabbithole.PolicyDefinition, len(spec.Definition))
+	for key, value := range spec.Definition {
+		definition[key] = value
+	}
+
+	return rabbithole.Policy{
+		Vhost:      vhost,
+		Name:       spec.Name,
+		Pattern:    spec.Pattern,
+		ApplyTo:    spec.ApplyTo,
+		Priority:   spec.Priority,
+		Definition: definition,
+	}
+}
+
+// Reconcile applies the desired policy to the broker pointed to by client,
+// creating or updating it as needed.
+func (builder *PolicyBuilder) Reconcile(client *rabbithole.Client) error {
+	policy := builder.Build()
+	if _, err := client.PutPolicy(policy.Vhost, policy.Name, policy); err != nil {
+		return fmt.Errorf("failed to reconcile policy %q on vhost %q: %v", policy.Name, policy.Vhost, err)
+	}
+	return nil
+}
+
+// Delete removes the policy from the broker pointed to by client. It is
+// called by RabbitmqPolicyReconciler when the RabbitmqPolicy is deleted, so
+// the broker doesn't keep enforcing a policy whose Kubernetes object no
+// longer exists.
+func (builder *PolicyBuilder) Delete(client *rabbithole.Client) error {
+	policy := builder.Build()
+	if _, err := client.DeletePolicy(policy.Vhost, policy.Name); err != nil {
+		return fmt.Errorf("failed to delete policy %q on vhost %q: %v", policy.Name, policy.Vhost, err)
+	}
+	return nil
+}
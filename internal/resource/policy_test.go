@@ -0,0 +1,57 @@
+package resource
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+)
+
+func TestPolicyBuilderBuild(t *testing.T) {
+	builder := &PolicyBuilder{
+		Policy: &rabbitmqv1beta1.RabbitmqPolicy{
+			Spec: rabbitmqv1beta1.RabbitmqPolicySpec{
+				Name:       "ha-all",
+				Pattern:    "^ha\\.",
+				ApplyTo:    "queues",
+				Priority:   10,
+				Definition: map[string]string{"ha-mode": "all"},
+			},
+		},
+	}
+
+	policy := builder.Build()
+
+	if policy.Vhost != "/" {
+		t.Errorf("Vhost = %q, want default \"/\"", policy.Vhost)
+	}
+	if policy.Name != "ha-all" {
+		t.Errorf("Name = %q, want %q", policy.Name, "ha-all")
+	}
+	if policy.Pattern != "^ha\\." {
+		t.Errorf("Pattern = %q, want %q", policy.Pattern, "^ha\\.")
+	}
+	if policy.ApplyTo != "queues" {
+		t.Errorf("ApplyTo = %q, want %q", policy.ApplyTo, "queues")
+	}
+	if policy.Priority != 10 {
+		t.Errorf("Priority = %d, want 10", policy.Priority)
+	}
+	if policy.Definition["ha-mode"] != "all" {
+		t.Errorf("Definition[ha-mode] = %v, want %q", policy.Definition["ha-mode"], "all")
+	}
+}
+
+func TestPolicyBuilderBuildExplicitVhost(t *testing.T) {
+	builder := &PolicyBuilder{
+		Policy: &rabbitmqv1beta1.RabbitmqPolicy{
+			Spec: rabbitmqv1beta1.RabbitmqPolicySpec{
+				Vhost: "my-vhost",
+				Name:  "ha-all",
+			},
+		},
+	}
+
+	if got := builder.Build().Vhost; got != "my-vhost" {
+		t.Errorf("Vhost = %q, want %q", got, "my-vhost")
+	}
+}
@@ -0,0 +1,18 @@
+package resource
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// randomHexString returns a cryptographically random hex string n bytes
+// long (2n hex characters), used to generate the admin password and erlang
+// cookie for secrets the operator owns itself.
+func randomHexString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
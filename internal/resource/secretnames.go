@@ -0,0 +1,34 @@
+package resource
+
+import (
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+)
+
+// AdminSecretName returns the name of the secret holding the admin
+// "username"/"password" credentials for instance: the user-provided
+// Spec.Secrets.AdminSecretRef when set, or the operator-generated admin
+// secret otherwise. Shared by StatefulSetBuilder (to mount it) and
+// BrokerClientForCluster (to authenticate against the HTTP API with it).
+func AdminSecretName(instance *rabbitmqv1beta1.RabbitmqCluster) string {
+	if ref := instance.Spec.Secrets.AdminSecretRef; ref != nil {
+		return ref.Name
+	}
+	return instance.ChildResourceName(adminSecretName)
+}
+
+// ErlangCookieSecretName returns the name of the secret holding the erlang
+// cookie for instance: the user-provided Spec.Secrets.ErlangCookieSecretRef
+// when set, or the operator-generated erlang cookie secret otherwise.
+func ErlangCookieSecretName(instance *rabbitmqv1beta1.RabbitmqCluster) string {
+	if ref := instance.Spec.Secrets.ErlangCookieSecretRef; ref != nil {
+		return ref.Name
+	}
+	return instance.ChildResourceName(erlangCookieName)
+}
+
+// ClientServiceName returns the name of the Service clients use to reach
+// instance, shared by ClientServiceBuilder (to build it) and
+// BrokerClientForCluster (to address it).
+func ClientServiceName(instance *rabbitmqv1beta1.RabbitmqCluster) string {
+	return instance.ChildResourceName(clientServiceName)
+}
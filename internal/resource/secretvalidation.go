@@ -0,0 +1,83 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RequiredAdminSecretKeys, RequiredErlangCookieSecretKeys and
+// RequiredTLSSecretKeys are the keys this operator expects to find in a
+// user-provided Spec.Secrets.AdminSecretRef, Spec.Secrets.ErlangCookieSecretRef
+// and Spec.TLS.SecretRef respectively, mirroring the keys it writes into
+// the secrets it generates itself.
+var (
+	RequiredAdminSecretKeys        = []string{"username", "password"}
+	RequiredErlangCookieSecretKeys = []string{".erlang.cookie"}
+	RequiredTLSSecretKeys          = []string{"tls.crt", "tls.key", "ca.crt"}
+)
+
+// ValidateSecretKeys checks that secret's Data contains every key in keys,
+// returning an error naming the first one missing.
+func ValidateSecretKeys(secret *corev1.Secret, keys ...string) error {
+	for _, key := range keys {
+		if _, ok := secret.Data[key]; !ok {
+			return fmt.Errorf("secret %q is missing required key %q", secret.Name, key)
+		}
+	}
+	return nil
+}
+
+// ValidateReferencedSecrets fetches and validates every externally-managed
+// secret instance references (Spec.Secrets.ErlangCookieSecretRef,
+// Spec.Secrets.AdminSecretRef, Spec.TLS.SecretRef), so a misconfigured
+// reference - a secret that exists but is missing a required key - is
+// caught before the generated StatefulSet ever mounts it. Refs that aren't
+// set are skipped. Intended to be called by the RabbitmqCluster controller
+// before it reconciles the StatefulSet for instance.
+func ValidateReferencedSecrets(ctx context.Context, k8sClient client.Client, instance *rabbitmqv1beta1.RabbitmqCluster) error {
+	if ref := instance.Spec.Secrets.ErlangCookieSecretRef; ref != nil {
+		if err := validateReferencedSecret(ctx, k8sClient, instance.Namespace, ref.Name, RequiredErlangCookieSecretKeys); err != nil {
+			return err
+		}
+	}
+
+	if ref := instance.Spec.Secrets.AdminSecretRef; ref != nil {
+		if err := validateReferencedSecret(ctx, k8sClient, instance.Namespace, ref.Name, RequiredAdminSecretKeys); err != nil {
+			return err
+		}
+	}
+
+	if ref := instance.Spec.TLS.SecretRef; ref != nil {
+		if err := validateReferencedSecret(ctx, k8sClient, instance.Namespace, ref.Name, RequiredTLSSecretKeys); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateReferencedSecret(ctx context.Context, k8sClient client.Client, namespace, name string, keys []string) error {
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, secret); err != nil {
+		return fmt.Errorf("failed to get referenced secret %q: %v", name, err)
+	}
+	return ValidateSecretKeys(secret, keys...)
+}
+
+// ShouldGenerateAdminSecret reports whether the operator should generate
+// and own the admin secret for instance, i.e. the user hasn't brought
+// their own via Spec.Secrets.AdminSecretRef.
+func ShouldGenerateAdminSecret(instance *rabbitmqv1beta1.RabbitmqCluster) bool {
+	return instance.Spec.Secrets.AdminSecretRef == nil
+}
+
+// ShouldGenerateErlangCookieSecret reports whether the operator should
+// generate and own the erlang cookie secret for instance, i.e. the user
+// hasn't brought their own via Spec.Secrets.ErlangCookieSecretRef.
+func ShouldGenerateErlangCookieSecret(instance *rabbitmqv1beta1.RabbitmqCluster) bool {
+	return instance.Spec.Secrets.ErlangCookieSecretRef == nil
+}
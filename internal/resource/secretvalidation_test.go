@@ -0,0 +1,35 @@
+package resource
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateSecretKeysOK(t *testing.T) {
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("s3cr3t"),
+		},
+	}
+
+	if err := ValidateSecretKeys(secret, RequiredAdminSecretKeys...); err != nil {
+		t.Errorf("ValidateSecretKeys() error = %v, want nil", err)
+	}
+}
+
+func TestValidateSecretKeysMissing(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-admin-secret"},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+		},
+	}
+
+	err := ValidateSecretKeys(secret, RequiredAdminSecretKeys...)
+	if err == nil {
+		t.Fatal("ValidateSecretKeys() error = nil, want an error for the missing \"password\" key")
+	}
+}
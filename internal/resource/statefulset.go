@@ -1,18 +1,23 @@
 package resource
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/pivotal/rabbitmq-for-kubernetes/internal/metadata"
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -25,18 +30,100 @@ func (builder *RabbitmqResourceBuilder) StatefulSet() *StatefulSetBuilder {
 	return &StatefulSetBuilder{
 		Instance: builder.Instance,
 		Scheme:   builder.Scheme,
+		Recorder: builder.Recorder,
+		Client:   builder.Client,
 	}
 }
 
 type StatefulSetBuilder struct {
 	Instance *rabbitmqv1beta1.RabbitmqCluster
 	Scheme   *runtime.Scheme
+	// Recorder emits the PodTemplateChanged/RollingRestartRequested
+	// events fired by setStatefulSetParams.
+	Recorder record.EventRecorder
+	// Client is used by loadReferencedResourceVersions to fetch the
+	// ConfigMap/Secrets mounted into the pod template (server config, admin
+	// secret, erlang cookie secret, TLS secret), so their resourceVersions
+	// can be folded into the pod template hash. Nil in callers that only
+	// ever Build (never Update/Matches against a live cluster).
+	Client client.Client
+	// ReferencedResourceVersions are the resourceVersions of the
+	// ConfigMap/Secrets mounted into the pod template. Update populates
+	// this itself via loadReferencedResourceVersions before hashing; it's
+	// exported so Matches (and tests) can set it directly without a Client.
+	ReferencedResourceVersions []string
+}
+
+func (builder *StatefulSetBuilder) Kind() SubResourceKind {
+	return StatefulSetKind
 }
 
 func (builder *StatefulSetBuilder) Build() (runtime.Object, error) {
 	return builder.statefulSet()
 }
 
+// Matches reports whether sts already reflects builder's desired state, so
+// the reconcile loop can skip the write when nothing has drifted. It runs
+// Update's steps on a copy of sts (without emitting events) and compares
+// the PodTemplateHashAnnotation that copy gets with the one sts carries,
+// rather than walking individual fields: the hash folds in every pod
+// template field Update sets (image, resources, affinity, volumes, ports,
+// env, ...) plus the resource versions of whatever ConfigMap/Secrets the
+// template references, so it also catches drift in externally-managed
+// secrets' contents. Replicas live outside the pod template and are
+// compared directly.
+// It also honours Spec.RollingRestart.Trigger, reporting drift whenever
+// the trigger has changed since the last stamped restart-requested-at
+// annotation - even if the template hash itself is unchanged.
+func (builder *StatefulSetBuilder) Matches(current runtime.Object) bool {
+	sts, ok := current.(*appsv1.StatefulSet)
+	if !ok {
+		return false
+	}
+
+	existingHash, ok := sts.Spec.Template.Annotations[PodTemplateHashAnnotation]
+	if !ok {
+		return false
+	}
+
+	desired := sts.DeepCopy()
+	desired.Spec.Template = builder.desiredPodTemplate(sts)
+	quiet := *builder
+	quiet.Recorder = nil
+	quiet.ReferencedResourceVersions = builder.referencedResourceVersions()
+	err := quiet.setStatefulSetParams(desired)
+	if err == nil {
+		err = quiet.applyOverride(desired)
+	}
+	if err != nil {
+		ctrl.Log.WithName("statefulset").WithName("RabbitmqCluster").Error(err, "failed to compute desired StatefulSet; assuming drift")
+		return false
+	}
+	if existingHash != desired.Spec.Template.Annotations[PodTemplateHashAnnotation] {
+		return false
+	}
+	if sts.Spec.Replicas == nil || desired.Spec.Replicas == nil || *sts.Spec.Replicas != *desired.Spec.Replicas {
+		return false
+	}
+
+	trigger := builder.Instance.Spec.RollingRestart.Trigger
+	return trigger == "" || sts.Spec.Template.Annotations[RestartRequestedAtAnnotation] == trigger
+}
+
+// desiredPodTemplate returns the pod template Update starts from before
+// setStatefulSetParams fills in the rest: podTemplateSpec's template, with
+// ObjectMeta.Annotations merged via metadata.ReconcileAnnotations against
+// sts's existing template annotations and the instance's own annotations.
+// Update and Matches both start from exactly this, not podTemplateSpec's
+// output alone - otherwise any RabbitmqCluster with non-empty Spec-level
+// annotations would hash differently in Matches than Update stamped, and
+// report permanent drift.
+func (builder *StatefulSetBuilder) desiredPodTemplate(sts *appsv1.StatefulSet) corev1.PodTemplateSpec {
+	template := builder.podTemplateSpec()
+	template.ObjectMeta.Annotations = metadata.ReconcileAnnotations(sts.Spec.Template.Annotations, builder.Instance.Annotations)
+	return template
+}
+
 func (builder *StatefulSetBuilder) statefulSet() (*appsv1.StatefulSet, error) {
 	// PVC, ServiceName & Selector: can't be updated without deleting the statefulset
 	pvc, err := persistentVolumeClaim(builder.Instance, builder.Scheme)
@@ -61,12 +148,120 @@ func (builder *StatefulSetBuilder) statefulSet() (*appsv1.StatefulSet, error) {
 
 func (builder *StatefulSetBuilder) Update(object runtime.Object) error {
 	sts := object.(*appsv1.StatefulSet)
-	podAnnotations := metadata.ReconcileAnnotations(sts.Spec.Template.Annotations, builder.Instance.Annotations)
 	annotations := metadata.ReconcileAnnotations(sts.Annotations, builder.Instance.Annotations)
-	sts.Spec.Template = builder.podTemplateSpec()
-	sts.Spec.Template.ObjectMeta.Annotations = podAnnotations
+	sts.Spec.Template = builder.desiredPodTemplate(sts)
 	sts.Annotations = annotations
-	return builder.setStatefulSetParams(sts)
+
+	if builder.Client != nil {
+		// SubResource.Update has no context parameter to thread through;
+		// context.Background is fine here since these are Gets against the
+		// controller-runtime cache, not a call worth cancelling.
+		versions, err := builder.loadReferencedResourceVersions(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to load referenced resource versions: %v", err)
+		}
+		builder.ReferencedResourceVersions = versions
+	}
+
+	if err := builder.setStatefulSetParams(sts); err != nil {
+		return err
+	}
+	return builder.applyOverride(sts)
+}
+
+// loadReferencedResourceVersions fetches the ConfigMap/Secrets mounted into
+// the pod template and returns their resourceVersions, so stampPodTemplateHash
+// can fold them into the pod template hash: the template's own bytes never
+// change when a referenced secret's contents do (the template only ever
+// holds the secret's name), so without this a rotated admin/erlang
+// cookie/TLS secret would never trigger a roll.
+func (builder *StatefulSetBuilder) loadReferencedResourceVersions(ctx context.Context) ([]string, error) {
+	var versions []string
+
+	configMap := &corev1.ConfigMap{}
+	configMapName := builder.Instance.ChildResourceName(serverConfigMapName)
+	if err := builder.Client.Get(ctx, client.ObjectKey{Name: configMapName, Namespace: builder.Instance.Namespace}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get server config map %q: %v", configMapName, err)
+	}
+	versions = append(versions, configMap.ResourceVersion)
+
+	adminSecret := &corev1.Secret{}
+	adminSecretRefName := AdminSecretName(builder.Instance)
+	if err := builder.Client.Get(ctx, client.ObjectKey{Name: adminSecretRefName, Namespace: builder.Instance.Namespace}, adminSecret); err != nil {
+		return nil, fmt.Errorf("failed to get admin secret %q: %v", adminSecretRefName, err)
+	}
+	versions = append(versions, adminSecret.ResourceVersion)
+
+	erlangCookieSecret := &corev1.Secret{}
+	erlangCookieSecretRefName := ErlangCookieSecretName(builder.Instance)
+	if err := builder.Client.Get(ctx, client.ObjectKey{Name: erlangCookieSecretRefName, Namespace: builder.Instance.Namespace}, erlangCookieSecret); err != nil {
+		return nil, fmt.Errorf("failed to get erlang cookie secret %q: %v", erlangCookieSecretRefName, err)
+	}
+	versions = append(versions, erlangCookieSecret.ResourceVersion)
+
+	if ref := builder.Instance.Spec.TLS.SecretRef; ref != nil {
+		tlsSecret := &corev1.Secret{}
+		if err := builder.Client.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: builder.Instance.Namespace}, tlsSecret); err != nil {
+			return nil, fmt.Errorf("failed to get TLS secret %q: %v", ref.Name, err)
+		}
+		versions = append(versions, tlsSecret.ResourceVersion)
+	}
+
+	return versions, nil
+}
+
+// referencedResourceVersions returns ReferencedResourceVersions as already
+// populated by Update, or - when Matches is called without a prior Update
+// in this reconcile (e.g. the very first comparison against a freshly
+// fetched object) - fetches them directly via Client. Errors are logged
+// and swallowed rather than returned, since Matches can only report a
+// bool; treating a fetch error as "assume drift" just costs an extra
+// reconcile instead of silently treating stale referenced secrets as
+// unchanged.
+func (builder *StatefulSetBuilder) referencedResourceVersions() []string {
+	if len(builder.ReferencedResourceVersions) > 0 {
+		return builder.ReferencedResourceVersions
+	}
+	if builder.Client == nil {
+		return nil
+	}
+
+	versions, err := builder.loadReferencedResourceVersions(context.Background())
+	if err != nil {
+		ctrl.Log.WithName("statefulset").WithName("RabbitmqCluster").Error(err, "failed to load referenced resource versions; assuming drift")
+		return nil
+	}
+	return versions
+}
+
+// applyOverride strategic-merge-patches sts with Spec.Override.StatefulSet,
+// when set, letting users customise fields (sidecars, extra volumes,
+// sysctls, priorityClassName, topologySpreadConstraints, container args,
+// ...) that aren't first-class on the CRD without forking the operator.
+// The operator still owns reconciliation of the merged result.
+func (builder *StatefulSetBuilder) applyOverride(sts *appsv1.StatefulSet) error {
+	override := builder.Instance.Spec.Override.StatefulSet
+	if override == nil || len(override.Raw) == 0 {
+		return nil
+	}
+
+	original, err := json.Marshal(sts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal StatefulSet for override: %v", err)
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(original, override.Raw, appsv1.StatefulSet{})
+	if err != nil {
+		return fmt.Errorf("failed to apply StatefulSet override: %v", err)
+	}
+
+	merged := appsv1.StatefulSet{}
+	if err := json.Unmarshal(patched, &merged); err != nil {
+		return fmt.Errorf("failed to unmarshal merged StatefulSet: %v", err)
+	}
+
+	*sts = merged
+	return nil
 }
 
 func (builder *StatefulSetBuilder) setStatefulSetParams(sts *appsv1.StatefulSet) error {
@@ -130,9 +325,53 @@ func (builder *StatefulSetBuilder) setStatefulSetParams(sts *appsv1.StatefulSet)
 	sts.Spec.Template.Spec.Affinity = builder.Instance.Spec.Affinity
 	sts.Spec.Template.Spec.Tolerations = builder.Instance.Spec.Tolerations
 
+	return builder.stampPodTemplateHash(sts)
+}
+
+// stampPodTemplateHash hashes the fully-assembled pod template together
+// with ReferencedResourceVersions and stores it under
+// PodTemplateHashAnnotation, so the StatefulSet controller rolls pods
+// whenever the template or anything it references actually changed -
+// instead of relying on ReconcileAnnotations, which only ever carries
+// user-set annotations through, never detects drift itself. It also honours
+// Spec.RollingRestart.Trigger: when it changes, RestartRequestedAtAnnotation
+// is stamped with the new trigger value, forcing a roll even if nothing
+// else did. Both cases emit an event recording why the roll happened.
+func (builder *StatefulSetBuilder) stampPodTemplateHash(sts *appsv1.StatefulSet) error {
+	if sts.Spec.Template.Annotations == nil {
+		sts.Spec.Template.Annotations = map[string]string{}
+	}
+
+	for key, value := range builder.basePodTemplateAnnotations() {
+		sts.Spec.Template.Annotations[key] = value
+	}
+
+	hash, err := podTemplateHash(sts.Spec.Template, builder.ReferencedResourceVersions...)
+	if err != nil {
+		return fmt.Errorf("failed to hash pod template: %v", err)
+	}
+
+	if previous := sts.Spec.Template.Annotations[PodTemplateHashAnnotation]; previous != "" && previous != hash {
+		builder.event(corev1.EventTypeNormal, "PodTemplateChanged", "RabbitMQ pod template or its referenced config/secrets changed; rolling StatefulSet")
+	}
+	sts.Spec.Template.Annotations[PodTemplateHashAnnotation] = hash
+
+	trigger := builder.Instance.Spec.RollingRestart.Trigger
+	if trigger != "" && sts.Spec.Template.Annotations[RestartRequestedAtAnnotation] != trigger {
+		builder.event(corev1.EventTypeNormal, "RollingRestartRequested", fmt.Sprintf("Rolling restart requested via Spec.RollingRestart.Trigger=%q", trigger))
+		sts.Spec.Template.Annotations[RestartRequestedAtAnnotation] = trigger
+	}
+
 	return nil
 }
 
+func (builder *StatefulSetBuilder) event(eventType, reason, message string) {
+	if builder.Recorder == nil {
+		return
+	}
+	builder.Recorder.Event(builder.Instance, eventType, reason, message)
+}
+
 func persistentVolumeClaim(instance *rabbitmqv1beta1.RabbitmqCluster, scheme *runtime.Scheme) ([]corev1.PersistentVolumeClaim, error) {
 	pvc := corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -165,7 +404,7 @@ func (builder *StatefulSetBuilder) podTemplateSpec() corev1.PodTemplateSpec {
 	rabbitmqUID := int64(999)
 
 	terminationGracePeriod := defaultGracePeriodTimeoutSeconds
-	return corev1.PodTemplateSpec{
+	template := corev1.PodTemplateSpec{
 		Spec: corev1.PodSpec{
 			SecurityContext: &corev1.PodSecurityContext{
 				FSGroup:    &rabbitmqGID,
@@ -259,24 +498,7 @@ func (builder *StatefulSetBuilder) podTemplateSpec() corev1.PodTemplateSpec {
 							Value: ".$(K8S_SERVICE_NAME).$(MY_POD_NAMESPACE).svc.cluster.local",
 						},
 					},
-					Ports: []corev1.ContainerPort{
-						{
-							Name:          "epmd",
-							ContainerPort: 4369,
-						},
-						{
-							Name:          "amqp",
-							ContainerPort: 5672,
-						},
-						{
-							Name:          "http",
-							ContainerPort: 15672,
-						},
-						{
-							Name:          "prometheus",
-							ContainerPort: 15692,
-						},
-					},
+					Ports: builder.pluginPorts(),
 					VolumeMounts: []corev1.VolumeMount{
 						{
 							Name:      "server-conf",
@@ -299,18 +521,7 @@ func (builder *StatefulSetBuilder) podTemplateSpec() corev1.PodTemplateSpec {
 							MountPath: "/var/lib/rabbitmq/",
 						},
 					},
-					ReadinessProbe: &corev1.Probe{
-						Handler: corev1.Handler{
-							Exec: &corev1.ExecAction{
-								Command: []string{"/bin/sh", "-c", "rabbitmq-diagnostics check_port_connectivity"},
-							},
-						},
-						InitialDelaySeconds: 10,
-						TimeoutSeconds:      5,
-						PeriodSeconds:       30,
-						SuccessThreshold:    1,
-						FailureThreshold:    3,
-					},
+					ReadinessProbe: builder.readinessProbe(),
 					Lifecycle: &corev1.Lifecycle{
 						PreStop: &corev1.Handler{
 							Exec: &corev1.ExecAction{
@@ -331,7 +542,7 @@ func (builder *StatefulSetBuilder) podTemplateSpec() corev1.PodTemplateSpec {
 					Name: "rabbitmq-admin",
 					VolumeSource: corev1.VolumeSource{
 						Secret: &corev1.SecretVolumeSource{
-							SecretName: builder.Instance.ChildResourceName(adminSecretName),
+							SecretName: builder.adminSecretName(),
 							Items: []corev1.KeyToPath{
 								{
 									Key:  "username",
@@ -371,11 +582,61 @@ func (builder *StatefulSetBuilder) podTemplateSpec() corev1.PodTemplateSpec {
 					Name: "erlang-cookie-secret",
 					VolumeSource: corev1.VolumeSource{
 						Secret: &corev1.SecretVolumeSource{
-							SecretName: builder.Instance.ChildResourceName(erlangCookieName),
+							SecretName: builder.erlangCookieSecretName(),
 						},
 					},
 				},
 			},
 		},
 	}
+
+	template.Spec.Containers[0].Env = append(template.Spec.Containers[0].Env, builder.pluginEnv()...)
+	builder.addTLS(&template)
+	return template
+}
+
+// adminSecretName returns the name of the secret mounted as the admin
+// credentials volume. See AdminSecretName.
+func (builder *StatefulSetBuilder) adminSecretName() string {
+	return AdminSecretName(builder.Instance)
+}
+
+// erlangCookieSecretName returns the name of the secret mounted as the
+// erlang cookie. See ErlangCookieSecretName.
+func (builder *StatefulSetBuilder) erlangCookieSecretName() string {
+	return ErlangCookieSecretName(builder.Instance)
+}
+
+// addTLS wires the AMQPS/HTTPS ports, RABBITMQ_SSL_* environment variables
+// and the rabbitmq-tls volume into template when Spec.TLS.SecretRef is set.
+// It is a no-op otherwise, leaving plaintext-only pods unchanged.
+func (builder *StatefulSetBuilder) addTLS(template *corev1.PodTemplateSpec) {
+	ref := builder.Instance.Spec.TLS.SecretRef
+	if ref == nil {
+		return
+	}
+
+	container := &template.Spec.Containers[0]
+	container.Ports = append(container.Ports,
+		corev1.ContainerPort{Name: "amqps", ContainerPort: 5671},
+		corev1.ContainerPort{Name: "https", ContainerPort: 15671},
+	)
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: "RABBITMQ_SSL_CERTFILE", Value: "/etc/rabbitmq-tls/tls.crt"},
+		corev1.EnvVar{Name: "RABBITMQ_SSL_KEYFILE", Value: "/etc/rabbitmq-tls/tls.key"},
+		corev1.EnvVar{Name: "RABBITMQ_SSL_CACERTFILE", Value: "/etc/rabbitmq-tls/ca.crt"},
+	)
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "rabbitmq-tls",
+		MountPath: "/etc/rabbitmq-tls/",
+	})
+
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: "rabbitmq-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: ref.Name,
+			},
+		},
+	})
 }
@@ -0,0 +1,68 @@
+package resource
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestApplyOverrideNoop(t *testing.T) {
+	builder := &StatefulSetBuilder{
+		Instance: &rabbitmqv1beta1.RabbitmqCluster{},
+	}
+	sts := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+	}
+
+	if err := builder.applyOverride(sts); err != nil {
+		t.Fatalf("applyOverride() error = %v", err)
+	}
+	if *sts.Spec.Replicas != 3 {
+		t.Errorf("Replicas = %d, want 3 (no override set, should be a no-op)", *sts.Spec.Replicas)
+	}
+}
+
+func TestApplyOverrideMergesAdditionalField(t *testing.T) {
+	builder := &StatefulSetBuilder{
+		Instance: &rabbitmqv1beta1.RabbitmqCluster{
+			Spec: rabbitmqv1beta1.RabbitmqClusterSpec{
+				Override: rabbitmqv1beta1.RabbitmqClusterOverrideSpec{
+					StatefulSet: &runtime.RawExtension{
+						Raw: []byte(`{"spec":{"template":{"spec":{"priorityClassName":"high"}}}}`),
+					},
+				},
+			},
+		},
+	}
+	sts := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "rabbitmq"}},
+				},
+			},
+		},
+	}
+
+	if err := builder.applyOverride(sts); err != nil {
+		t.Fatalf("applyOverride() error = %v", err)
+	}
+
+	if *sts.Spec.Replicas != 3 {
+		t.Errorf("Replicas = %d, want 3 (override shouldn't touch fields it doesn't mention)", *sts.Spec.Replicas)
+	}
+	if sts.Spec.Template.Spec.PriorityClassName != "high" {
+		t.Errorf("PriorityClassName = %q, want %q", sts.Spec.Template.Spec.PriorityClassName, "high")
+	}
+	if len(sts.Spec.Template.Spec.Containers) != 1 || sts.Spec.Template.Spec.Containers[0].Name != "rabbitmq" {
+		t.Errorf("Containers = %v, want the original rabbitmq container preserved", sts.Spec.Template.Spec.Containers)
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
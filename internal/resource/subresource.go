@@ -0,0 +1,118 @@
+package resource
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SubResourceKind identifies one of the child resources that make up a
+// RabbitmqCluster, so the reconcile loop and its logs/metrics can refer to
+// a specific sub-resource rather than "the statefulset" or "some object".
+type SubResourceKind string
+
+const (
+	ServerConfigMapKind SubResourceKind = "ServerConfigMap"
+	AdminSecretKind     SubResourceKind = "AdminSecret"
+	ErlangCookieKind    SubResourceKind = "ErlangCookie"
+	HeadlessServiceKind SubResourceKind = "HeadlessService"
+	ClientServiceKind   SubResourceKind = "ClientService"
+	ServiceAccountKind  SubResourceKind = "ServiceAccount"
+	RoleKind            SubResourceKind = "Role"
+	RoleBindingKind     SubResourceKind = "RoleBinding"
+	StatefulSetKind     SubResourceKind = "StatefulSet"
+)
+
+// SubResource is implemented by every builder in this package that produces
+// a child Kubernetes object of a RabbitmqCluster. It replaces the ad hoc
+// Build/Update pair with a contract the reconcile loop can drive uniformly:
+// build the desired object, detect whether the current object already
+// matches it, and only call Update - and therefore only write to the API
+// server - when it doesn't.
+type SubResource interface {
+	// Kind identifies which child resource this SubResource builds, for
+	// logging and metrics.
+	Kind() SubResourceKind
+	// Build returns a new object carrying only what has to be set when it
+	// is first created (name, namespace, fields that can't be updated in
+	// place). It is only ever called to create the object for the first
+	// time, and ReconcileSubResource runs Update on the result before it is
+	// written.
+	Build() (runtime.Object, error)
+	// Update mutates current in place so that it matches the desired
+	// state.
+	Update(current runtime.Object) error
+	// Matches reports whether current already reflects the desired state,
+	// i.e. whether Update would be a no-op. The reconcile loop uses this
+	// to skip writes when nothing has drifted.
+	Matches(current runtime.Object) bool
+}
+
+// ReconcileSubResource builds current into its desired state if current
+// already exists, or builds a brand new object and runs it through Update
+// as well otherwise - Build alone only ever sets the fields that can't be
+// updated in place later (see StatefulSetBuilder.Build), so skipping Update
+// on creation would hand the caller an incomplete object. It reports
+// whether a write is required so callers can log/emit metrics per
+// SubResourceKind without duplicating the Matches check.
+func ReconcileSubResource(sub SubResource, current runtime.Object, exists bool) (desired runtime.Object, needsWrite bool, err error) {
+	if !exists {
+		desired, err = sub.Build()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to build %s: %v", sub.Kind(), err)
+		}
+		if err := sub.Update(desired); err != nil {
+			return nil, false, fmt.Errorf("failed to update newly built %s: %v", sub.Kind(), err)
+		}
+		return desired, true, nil
+	}
+
+	if sub.Matches(current) {
+		return current, false, nil
+	}
+
+	if err := sub.Update(current); err != nil {
+		return nil, false, fmt.Errorf("failed to update %s: %v", sub.Kind(), err)
+	}
+	return current, true, nil
+}
+
+// ReconcileSubResources drives ReconcileSubResource for every sub in subs,
+// in order, so a controller can iterate "every sub-resource" from one call
+// instead of hand-rolling the loop per kind. getCurrent is expected to
+// fetch the existing child object for sub.Kind(), reporting exists=false
+// when it doesn't exist yet (e.g. a client.Get that returned NotFound).
+// write is called with the resulting desired object whenever needsWrite is
+// true, so the caller decides how that object actually reaches the API
+// server (Create vs. Update). It returns the kinds that drifted and were
+// written, in iteration order, so callers can log/emit metrics about
+// exactly which sub-resources reconverged.
+func ReconcileSubResources(
+	subs []SubResource,
+	getCurrent func(kind SubResourceKind) (current runtime.Object, exists bool, err error),
+	write func(kind SubResourceKind, desired runtime.Object, exists bool) error,
+) ([]SubResourceKind, error) {
+	var drifted []SubResourceKind
+
+	for _, sub := range subs {
+		current, exists, err := getCurrent(sub.Kind())
+		if err != nil {
+			return drifted, fmt.Errorf("failed to get current state of %s: %v", sub.Kind(), err)
+		}
+
+		desired, needsWrite, err := ReconcileSubResource(sub, current, exists)
+		if err != nil {
+			return drifted, err
+		}
+		if !needsWrite {
+			continue
+		}
+
+		if err := write(sub.Kind(), desired, exists); err != nil {
+			return drifted, fmt.Errorf("failed to write %s: %v", sub.Kind(), err)
+		}
+		drifted = append(drifted, sub.Kind())
+	}
+
+	return drifted, nil
+}
@@ -0,0 +1,56 @@
+package resource
+
+import (
+	"fmt"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func (builder *RabbitmqResourceBuilder) Vhost(vhost *rabbitmqv1beta1.RabbitmqVhost) *VhostBuilder {
+	return &VhostBuilder{
+		Instance: builder.Instance,
+		Scheme:   builder.Scheme,
+		Vhost:    vhost,
+	}
+}
+
+// VhostBuilder reconciles a RabbitmqVhost against the RabbitMQ HTTP API of
+// the broker owning Instance. As with PolicyBuilder, there is no backing
+// Kubernetes object, so reconciliation is exposed as Reconcile rather than
+// Update.
+type VhostBuilder struct {
+	Instance *rabbitmqv1beta1.RabbitmqCluster
+	Scheme   *runtime.Scheme
+	Vhost    *rabbitmqv1beta1.RabbitmqVhost
+}
+
+// Build returns the rabbithole vhost settings ready to be sent to the HTTP
+// API.
+func (builder *VhostBuilder) Build() rabbithole.VhostSettings {
+	return rabbithole.VhostSettings{
+		Tracing: builder.Vhost.Spec.Tracing,
+	}
+}
+
+// Reconcile declares the desired vhost on the broker pointed to by client,
+// creating or updating it as needed.
+func (builder *VhostBuilder) Reconcile(client *rabbithole.Client) error {
+	name := builder.Vhost.Spec.Name
+	if _, err := client.PutVhost(name, builder.Build()); err != nil {
+		return fmt.Errorf("failed to reconcile vhost %q: %v", name, err)
+	}
+	return nil
+}
+
+// Delete removes the vhost from the broker pointed to by client. It is
+// called by RabbitmqVhostReconciler when the RabbitmqVhost is deleted, so
+// the vhost doesn't outlive its Kubernetes object.
+func (builder *VhostBuilder) Delete(client *rabbithole.Client) error {
+	name := builder.Vhost.Spec.Name
+	if _, err := client.DeleteVhost(name); err != nil {
+		return fmt.Errorf("failed to delete vhost %q: %v", name, err)
+	}
+	return nil
+}
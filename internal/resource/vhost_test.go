@@ -0,0 +1,22 @@
+package resource
+
+import (
+	"testing"
+
+	rabbitmqv1beta1 "github.com/pivotal/rabbitmq-for-kubernetes/api/v1beta1"
+)
+
+func TestVhostBuilderBuild(t *testing.T) {
+	builder := &VhostBuilder{
+		Vhost: &rabbitmqv1beta1.RabbitmqVhost{
+			Spec: rabbitmqv1beta1.RabbitmqVhostSpec{
+				Name:    "my-vhost",
+				Tracing: true,
+			},
+		},
+	}
+
+	if settings := builder.Build(); !settings.Tracing {
+		t.Errorf("Tracing = %v, want true", settings.Tracing)
+	}
+}